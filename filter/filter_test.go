@@ -0,0 +1,200 @@
+package filter_test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"github.com/galihrivanto/bun-m2m/filter"
+	"github.com/galihrivanto/bun-m2m/migrations"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+// newTestDB seeds three orders and three items: order 1 has items 1 and 2,
+// order 2 has item 1, order 3 has no items at all.
+func newTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := migrations.Run(ctx, db, "up"); err != nil {
+		t.Fatal(err)
+	}
+	db.RegisterModel((*models.OrderToItemM2M)(nil))
+
+	orders := []*models.Order{{ID: 1}, {ID: 2}, {ID: 3}}
+	if _, err := db.NewInsert().Model(&orders).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	items := []*models.Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pairs := []*models.OrderToItem{
+		{OrderID: 1, ItemID: 1},
+		{OrderID: 1, ItemID: 2},
+		{OrderID: 2, ItemID: 1},
+	}
+	if _, err := db.NewInsert().Model(&pairs).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func orderIDs(t *testing.T, ctx context.Context, q *bun.SelectQuery) []int64 {
+	t.Helper()
+
+	var orders []models.OrderM2M
+	if err := q.Scan(ctx, &orders); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]int64, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestWhereHasAny(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{"item 2 only on order 1", []int64{2}, []int64{1}},
+		{"item 1 on orders 1 and 2", []int64{1}, []int64{1, 2}},
+		{"item not attached to anything", []int64{3}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var orders []models.OrderM2M
+			q := db.NewSelect().Model(&orders)
+			q = filter.WhereHasAny(q, "ItemsM2M.Item", tt.ids)
+
+			got := orderIDs(t, ctx, q)
+			assertIDs(t, got, tt.want)
+		})
+	}
+}
+
+func TestWhereHasAll(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{"order 1 has both 1 and 2", []int64{1, 2}, []int64{1}},
+		{"no order has 1, 2 and 3", []int64{1, 2, 3}, nil},
+		{"every order trivially has no items", nil, []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var orders []models.OrderM2M
+			q := db.NewSelect().Model(&orders)
+			q = filter.WhereHasAll(q, "ItemsM2M.Item", tt.ids)
+
+			got := orderIDs(t, ctx, q)
+			assertIDs(t, got, tt.want)
+		})
+	}
+}
+
+func TestWhereHasNone(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{"item 1 attached to orders 1 and 2", []int64{1}, []int64{3}},
+		{"item 2 attached to order 1 only", []int64{2}, []int64{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var orders []models.OrderM2M
+			q := db.NewSelect().Model(&orders)
+			q = filter.WhereHasNone(q, "ItemsM2M.Item", tt.ids)
+
+			got := orderIDs(t, ctx, q)
+			assertIDs(t, got, tt.want)
+		})
+	}
+}
+
+func TestOrderFilterApply(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	f := filter.OrderFilter{
+		IDs:           []int64{1, 2, 3},
+		HasAnyItemIDs: []int64{1},
+	}
+
+	var orders []models.OrderM2M
+	q := f.Apply(db.NewSelect().Model(&orders))
+
+	got := orderIDs(t, ctx, q)
+	assertIDs(t, got, []int64{1, 2})
+}
+
+func TestItemFilterApply(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	f := filter.ItemFilter{
+		HasAnyOrderIDs: []int64{2},
+	}
+
+	var items []models.ItemM2M
+	q := f.Apply(db.NewSelect().Model(&items))
+	if err := q.Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]int64, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// only item 1 is attached to order 2
+	assertIDs(t, ids, []int64{1})
+}
+
+func assertIDs(t *testing.T, got, want []int64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("ids = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("ids = %v, want %v", got, want)
+		}
+	}
+}
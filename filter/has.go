@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// WhereHasAny restricts q to owner rows that are linked, through relation
+// (e.g. "ItemsM2M.Item"), to at least one of ids.
+func WhereHasAny(q *bun.SelectQuery, relation string, ids []int64) *bun.SelectQuery {
+	desc, err := lookup(relation)
+	if err != nil {
+		return q.Err(err)
+	}
+	if len(ids) == 0 {
+		return q
+	}
+
+	query := fmt.Sprintf(
+		`EXISTS (SELECT 1 FROM %[1]s WHERE %[1]s.%[2]s = ?TableAlias.%[3]s AND %[1]s.%[4]s IN (?))`,
+		desc.JoinTable, desc.OwnerColumn, desc.OwnerPK, desc.RelatedColumn,
+	)
+	return q.Where(query, bun.In(ids))
+}
+
+// WhereHasNone restricts q to owner rows that are linked, through
+// relation, to none of ids.
+func WhereHasNone(q *bun.SelectQuery, relation string, ids []int64) *bun.SelectQuery {
+	desc, err := lookup(relation)
+	if err != nil {
+		return q.Err(err)
+	}
+	if len(ids) == 0 {
+		return q
+	}
+
+	query := fmt.Sprintf(
+		`NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[1]s.%[2]s = ?TableAlias.%[3]s AND %[1]s.%[4]s IN (?))`,
+		desc.JoinTable, desc.OwnerColumn, desc.OwnerPK, desc.RelatedColumn,
+	)
+	return q.Where(query, bun.In(ids))
+}
+
+// WhereHasAll restricts q to owner rows that are linked, through
+// relation, to every one of ids.
+func WhereHasAll(q *bun.SelectQuery, relation string, ids []int64) *bun.SelectQuery {
+	desc, err := lookup(relation)
+	if err != nil {
+		return q.Err(err)
+	}
+	if len(ids) == 0 {
+		return q
+	}
+
+	query := fmt.Sprintf(
+		`(SELECT COUNT(DISTINCT %[1]s.%[2]s) FROM %[1]s WHERE %[1]s.%[3]s = ?TableAlias.%[4]s AND %[1]s.%[2]s IN (?)) = ?`,
+		desc.JoinTable, desc.RelatedColumn, desc.OwnerColumn, desc.OwnerPK,
+	)
+	return q.Where(query, bun.In(ids), len(uniqueInt64s(ids)))
+}
+
+func uniqueInt64s(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
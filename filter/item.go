@@ -0,0 +1,28 @@
+package filter
+
+import (
+	"github.com/uptrace/bun"
+)
+
+// ItemFilter is a typed set of predicates for querying items, including
+// the M2M ones ("items belonging to all/any/none of these orders").
+type ItemFilter struct {
+	IDs []int64
+
+	HasAnyOrderIDs  []int64
+	HasAllOrderIDs  []int64
+	HasNoneOrderIDs []int64
+}
+
+// Apply adds f's predicates to q and returns it.
+func (f ItemFilter) Apply(q *bun.SelectQuery) *bun.SelectQuery {
+	if len(f.IDs) > 0 {
+		q = q.Where("?TableAlias.id IN (?)", bun.In(f.IDs))
+	}
+
+	q = WhereHasAny(q, "OrdersM2M.Order", f.HasAnyOrderIDs)
+	q = WhereHasAll(q, "OrdersM2M.Order", f.HasAllOrderIDs)
+	q = WhereHasNone(q, "OrdersM2M.Order", f.HasNoneOrderIDs)
+
+	return q
+}
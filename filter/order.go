@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"github.com/uptrace/bun"
+)
+
+// OrderFilter is a typed set of predicates for querying orders, including
+// the M2M ones ("orders that contain all/any/none of these items") that
+// would otherwise need a hand-written EXISTS subquery.
+type OrderFilter struct {
+	IDs []int64
+
+	HasAnyItemIDs  []int64
+	HasAllItemIDs  []int64
+	HasNoneItemIDs []int64
+}
+
+// Apply adds f's predicates to q and returns it.
+func (f OrderFilter) Apply(q *bun.SelectQuery) *bun.SelectQuery {
+	if len(f.IDs) > 0 {
+		q = q.Where("?TableAlias.id IN (?)", bun.In(f.IDs))
+	}
+
+	q = WhereHasAny(q, "ItemsM2M.Item", f.HasAnyItemIDs)
+	q = WhereHasAll(q, "ItemsM2M.Item", f.HasAllItemIDs)
+	q = WhereHasNone(q, "ItemsM2M.Item", f.HasNoneItemIDs)
+
+	return q
+}
@@ -0,0 +1,52 @@
+// Package filter adds squirrel-style dynamic filtering on top of bun's
+// query builder: typed filter structs for Order and Item, and
+// WhereHasAll/WhereHasAny/WhereHasNone helpers that turn an M2M relation
+// name (e.g. "ItemsM2M.Item") and a set of ids into the right
+// WHERE EXISTS (...) subquery, so callers don't have to hand-write the
+// join the way selectUsingHasMany does in package main.
+package filter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/galihrivanto/bun-m2m/m2m"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+// relations caches the join metadata for every relation name the filter
+// helpers know about, resolved once via m2m.Describe against the same
+// bun tags OrderM2M/ItemM2M/OrderToItemM2M already carry.
+var relations = map[string]*m2m.Descriptor{}
+var relationsMu sync.RWMutex
+
+func init() {
+	mustRegister("ItemsM2M.Item",
+		(*models.OrderM2M)(nil), (*models.OrderToItemM2M)(nil), (*models.ItemM2M)(nil),
+		"ItemsM2M", "Item")
+	mustRegister("OrdersM2M.Order",
+		(*models.ItemM2M)(nil), (*models.OrderToItemM2M)(nil), (*models.OrderM2M)(nil),
+		"OrdersM2M", "Order")
+}
+
+func mustRegister(relation string, owner, join, related interface{}, ownerField, relatedField string) {
+	desc, err := m2m.Describe(owner, join, related, ownerField, relatedField)
+	if err != nil {
+		panic(fmt.Sprintf("filter: register relation %q: %v", relation, err))
+	}
+
+	relationsMu.Lock()
+	defer relationsMu.Unlock()
+	relations[relation] = desc
+}
+
+func lookup(relation string) (*m2m.Descriptor, error) {
+	relationsMu.RLock()
+	defer relationsMu.RUnlock()
+
+	desc, ok := relations[relation]
+	if !ok {
+		return nil, fmt.Errorf("filter: unknown relation %q", relation)
+	}
+	return desc, nil
+}
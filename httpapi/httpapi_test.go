@@ -0,0 +1,213 @@
+package httpapi_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"github.com/galihrivanto/bun-m2m/httpapi"
+	"github.com/galihrivanto/bun-m2m/migrations"
+	"github.com/galihrivanto/bun-m2m/models"
+	"github.com/galihrivanto/bun-m2m/registry"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := migrations.Run(ctx, db, "up"); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := registry.New(db)
+	reg.Register((*models.OrderToItemM2M)(nil))
+
+	if _, err := db.NewInsert().Model(&models.Order{ID: 1}).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	items := []*models.Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := httpapi.NewServer(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(srv.Routes())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// doJSON issues method against url with an optional JSON body, returning
+// the response without calling any *testing.T method — safe to use from
+// goroutines other than the test's own.
+func doJSON(method, url string, body interface{}) (*http.Response, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return http.DefaultClient.Do(req)
+}
+
+func postJSON(t *testing.T, url string, body interface{}) *http.Response {
+	t.Helper()
+
+	resp, err := doJSON(http.MethodPost, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestCreateOrderAndItem(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := postJSON(t, ts.URL+"/orders", map[string]interface{}{"id": 42})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create order status = %d", resp.StatusCode)
+	}
+
+	resp = postJSON(t, ts.URL+"/items", map[string]interface{}{"id": 99})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create item status = %d", resp.StatusCode)
+	}
+}
+
+func TestAttachDetachAndSearch(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := postJSON(t, ts.URL+"/orders/1/items", map[string]interface{}{"item_id": 1})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("attach status = %d", resp.StatusCode)
+	}
+
+	resp = postJSON(t, ts.URL+"/orders/search", map[string]interface{}{
+		"page": 1, "size": 10,
+		"filters": map[string]interface{}{"has_any_item_ids": []int{1}},
+	})
+	defer resp.Body.Close()
+
+	var got httpapi.SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 1 {
+		t.Fatalf("total = %d, want 1", got.Total)
+	}
+
+	resp2, err := doJSON(http.MethodDelete, ts.URL+"/orders/1/items/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusNoContent {
+		t.Fatalf("detach status = %d", resp2.StatusCode)
+	}
+}
+
+// TestConcurrentSearchWhileAttaching hits /orders/search and /items/search
+// from many goroutines while other goroutines concurrently attach and
+// detach items on order 1, exercising the M2M reads under HTTP
+// concurrency the way the request behind this package asked for.
+func TestConcurrentSearchWhileAttaching(t *testing.T) {
+	ts := newTestServer(t)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*3)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			itemID := (i % 3) + 1
+
+			resp, err := doJSON(http.MethodPost, fmt.Sprintf("%s/orders/1/items", ts.URL), map[string]interface{}{"item_id": itemID})
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				errs <- fmt.Errorf("attach status = %d", resp.StatusCode)
+			}
+
+			resp, err = doJSON(http.MethodDelete, fmt.Sprintf("%s/orders/1/items/%d", ts.URL, itemID), nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				errs <- fmt.Errorf("detach status = %d", resp.StatusCode)
+			}
+		}(i)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := doJSON(http.MethodPost, ts.URL+"/orders/search", map[string]interface{}{"page": 1, "size": 10})
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				errs <- fmt.Errorf("orders search status = %d", resp.StatusCode)
+			}
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := doJSON(http.MethodPost, ts.URL+"/items/search", map[string]interface{}{"page": 1, "size": 10})
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				errs <- fmt.Errorf("items search status = %d", resp.StatusCode)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+func (s *Server) listItems(w http.ResponseWriter, r *http.Request) {
+	var items []models.ItemM2M
+	if err := s.db.NewSelect().
+		Model(&items).
+		Relation("OrdersM2M").
+		Relation("OrdersM2M.Order").
+		Scan(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i := range items {
+		items[i].PostQuery()
+	}
+
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) createItem(w http.ResponseWriter, r *http.Request) {
+	var item models.Item
+	if err := decodeJSON(r, &item); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.db.NewInsert().Model(&item).Exec(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, item)
+}
@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/galihrivanto/bun-m2m/m2m"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+func (s *Server) listOrders(w http.ResponseWriter, r *http.Request) {
+	var orders []models.OrderM2M
+	if err := s.db.NewSelect().
+		Model(&orders).
+		Relation("ItemsM2M").
+		Relation("ItemsM2M.Item").
+		Scan(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i := range orders {
+		orders[i].PostQuery()
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, r *http.Request) {
+	var order models.Order
+	if err := decodeJSON(r, &order); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.db.NewInsert().Model(&order).Exec(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, order)
+}
+
+// attachItem handles POST /orders/{id}/items, attaching the item named in
+// the request body to the order named in the path.
+func (s *Server) attachItem(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var body struct {
+		ItemID int64 `json:"item_id"`
+	}
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	assoc := m2m.New(s.db, s.orderItems)
+	if err := assoc.Attach(r.Context(), &models.OrderM2M{ID: orderID}, &models.ItemM2M{ID: body.ItemID}); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// detachItem handles DELETE /orders/{id}/items/{itemID}.
+func (s *Server) detachItem(w http.ResponseWriter, r *http.Request) {
+	orderID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(r.PathValue("itemID"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	assoc := m2m.New(s.db, s.orderItems)
+	if err := assoc.Detach(r.Context(), &models.OrderM2M{ID: orderID}, &models.ItemM2M{ID: itemID}); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/galihrivanto/bun-m2m/filter"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+// SearchRequest is the body accepted by /orders/search and /items/search:
+// a page (1-based), a page size, and a set of named filters.
+type SearchRequest struct {
+	Page    int                    `json:"page"`
+	Size    int                    `json:"size"`
+	Filters map[string]interface{} `json:"filters"`
+}
+
+// SearchResponse is returned by the search endpoints.
+type SearchResponse struct {
+	List  interface{} `json:"list"`
+	Total int         `json:"total"`
+}
+
+func (r *SearchRequest) normalize() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.Size < 1 || r.Size > 100 {
+		r.Size = 20
+	}
+}
+
+func (s *Server) searchOrders(w http.ResponseWriter, r *http.Request) {
+	var req SearchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.normalize()
+
+	f := filter.OrderFilter{
+		IDs:            toInt64s(req.Filters["ids"]),
+		HasAnyItemIDs:  toInt64s(req.Filters["has_any_item_ids"]),
+		HasAllItemIDs:  toInt64s(req.Filters["has_all_item_ids"]),
+		HasNoneItemIDs: toInt64s(req.Filters["has_none_item_ids"]),
+	}
+
+	var orders []models.OrderM2M
+	q := s.db.NewSelect().
+		Model(&orders).
+		Relation("ItemsM2M").
+		Relation("ItemsM2M.Item")
+	q = f.Apply(q)
+
+	total, err := q.Limit(req.Size).Offset((req.Page - 1) * req.Size).ScanAndCount(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i := range orders {
+		orders[i].PostQuery()
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{List: orders, Total: total})
+}
+
+func (s *Server) searchItems(w http.ResponseWriter, r *http.Request) {
+	var req SearchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.normalize()
+
+	f := filter.ItemFilter{
+		IDs:             toInt64s(req.Filters["ids"]),
+		HasAnyOrderIDs:  toInt64s(req.Filters["has_any_order_ids"]),
+		HasAllOrderIDs:  toInt64s(req.Filters["has_all_order_ids"]),
+		HasNoneOrderIDs: toInt64s(req.Filters["has_none_order_ids"]),
+	}
+
+	var items []models.ItemM2M
+	q := s.db.NewSelect().
+		Model(&items).
+		Relation("OrdersM2M").
+		Relation("OrdersM2M.Order")
+	q = f.Apply(q)
+
+	total, err := q.Limit(req.Size).Offset((req.Page - 1) * req.Size).ScanAndCount(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i := range items {
+		items[i].PostQuery()
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{List: items, Total: total})
+}
+
+// toInt64s converts a filter value decoded from JSON (a []interface{} of
+// float64, per encoding/json's default number type) into []int64.
+func toInt64s(value interface{}) []int64 {
+	raw, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	out := make([]int64, 0, len(raw))
+	for _, v := range raw {
+		if n, ok := v.(float64); ok {
+			out = append(out, int64(n))
+		}
+	}
+	return out
+}
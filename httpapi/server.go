@@ -0,0 +1,52 @@
+// Package httpapi exposes the Order/Item M2M demo as a small JSON service:
+// plain CRUD for orders and items, attach/detach endpoints for the
+// order<->item association, and search endpoints that turn a page/size/
+// filters request body into a paginated, relation-eager-loaded query.
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/uptrace/bun"
+
+	"github.com/galihrivanto/bun-m2m/m2m"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+// Server holds the dependencies every handler needs: the database, and the
+// join descriptor used to attach/detach items on an order.
+type Server struct {
+	db         *bun.DB
+	orderItems *m2m.Descriptor
+}
+
+// NewServer builds a Server. db's models are expected to already be
+// registered (see registry.ModelRegistry) before any handler runs.
+func NewServer(db *bun.DB) (*Server, error) {
+	orderItems, err := m2m.Describe(
+		(*models.OrderM2M)(nil), (*models.OrderToItemM2M)(nil), (*models.ItemM2M)(nil),
+		"ItemsM2M", "Item",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{db: db, orderItems: orderItems}, nil
+}
+
+// Routes returns the service's handler, ready to pass to http.ListenAndServe.
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /orders", s.listOrders)
+	mux.HandleFunc("POST /orders", s.createOrder)
+	mux.HandleFunc("POST /orders/search", s.searchOrders)
+	mux.HandleFunc("POST /orders/{id}/items", s.attachItem)
+	mux.HandleFunc("DELETE /orders/{id}/items/{itemID}", s.detachItem)
+
+	mux.HandleFunc("GET /items", s.listItems)
+	mux.HandleFunc("POST /items", s.createItem)
+	mux.HandleFunc("POST /items/search", s.searchItems)
+
+	return mux
+}
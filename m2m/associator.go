@@ -0,0 +1,376 @@
+package m2m
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// Associator writes and maintains join rows for a single owner -> related
+// many-to-many relation, as described by a Descriptor. Reads still go
+// through bun's normal Relation()/PostQuery() path (see OrderM2M,
+// ItemM2M); Associator only covers the writes bun itself doesn't offer.
+//
+// Related model primary keys are assumed to be int64, matching every
+// model (Order, Item, ...) in this package's target repo.
+type Associator struct {
+	db   bun.IDB
+	desc *Descriptor
+}
+
+// New builds an Associator bound to db (a *bun.DB or a bun.Tx) using the
+// join metadata in desc.
+func New(db bun.IDB, desc *Descriptor) *Associator {
+	return &Associator{db: db, desc: desc}
+}
+
+// Row pairs a related model with extra pivot columns to set on its join
+// row (e.g. {"quantity": 2}), for callers whose join table carries more
+// than the owner/related FKs. Columns may be nil.
+type Row struct {
+	Related interface{}
+	Columns map[string]interface{}
+}
+
+// Attach inserts join rows linking owner to each of related, ignoring pairs
+// that are already attached. It does not set any pivot columns beyond the
+// owner/related FKs; use AttachRows for that.
+func (a *Associator) Attach(ctx context.Context, owner interface{}, related ...interface{}) error {
+	rows := make([]Row, len(related))
+	for i, rel := range related {
+		rows[i] = Row{Related: rel}
+	}
+	return a.AttachRows(ctx, owner, rows...)
+}
+
+// AttachRows inserts join rows linking owner to each row's related model,
+// setting any extra pivot columns given in row.Columns, and ignoring pairs
+// that are already attached.
+func (a *Associator) AttachRows(ctx context.Context, owner interface{}, rows ...Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ownerPK, err := pkValue(owner, a.desc.OwnerPK)
+	if err != nil {
+		return err
+	}
+
+	rowType := reflect.PtrTo(a.desc.joinType)
+	out := reflect.New(reflect.SliceOf(rowType)).Elem()
+	for _, r := range rows {
+		relatedPK, err := pkValue(r.Related, a.desc.RelatedPK)
+		if err != nil {
+			return err
+		}
+
+		joinRow := reflect.New(a.desc.joinType)
+		if err := setField(joinRow.Elem(), a.desc.OwnerColumn, ownerPK); err != nil {
+			return err
+		}
+		if err := setField(joinRow.Elem(), a.desc.RelatedColumn, relatedPK); err != nil {
+			return err
+		}
+		for column, value := range r.Columns {
+			if err := setField(joinRow.Elem(), column, value); err != nil {
+				return err
+			}
+		}
+		out = reflect.Append(out, joinRow)
+	}
+
+	outPtr := reflect.New(out.Type())
+	outPtr.Elem().Set(out)
+
+	_, err = a.db.NewInsert().Model(outPtr.Interface()).On("CONFLICT DO NOTHING").Exec(ctx)
+	return err
+}
+
+// Detach removes the join rows linking owner to each of related, leaving
+// any other association untouched.
+func (a *Associator) Detach(ctx context.Context, owner interface{}, related ...interface{}) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	ownerPK, err := pkValue(owner, a.desc.OwnerPK)
+	if err != nil {
+		return err
+	}
+
+	relatedPKs, err := pkValues(related, a.desc.RelatedPK)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.NewDelete().
+		Model(reflect.New(a.desc.joinType).Interface()).
+		Where("? = ?", bun.Ident(a.desc.OwnerColumn), ownerPK).
+		Where("? IN (?)", bun.Ident(a.desc.RelatedColumn), bun.In(relatedPKs)).
+		Exec(ctx)
+	return err
+}
+
+// Sync makes owner's association set exactly match related: rows for
+// anything missing are inserted, rows for anything no longer wanted are
+// removed, and rows already correct are left alone. It runs in a single
+// transaction. Sync itself never carries pivot columns (see Row), so there
+// is nothing to upsert on rows that stay attached; use SyncRows to also
+// upsert pivot columns on those.
+func (a *Associator) Sync(ctx context.Context, owner interface{}, related ...interface{}) error {
+	rows := make([]Row, len(related))
+	for i, rel := range related {
+		rows[i] = Row{Related: rel}
+	}
+	return a.SyncRows(ctx, owner, rows...)
+}
+
+// SyncRows is Sync with pivot columns: rows newly inserted get row.Columns
+// set, same as AttachRows, and rows that stay attached have row.Columns
+// upserted onto the existing join row (rows with a nil or empty Columns
+// are left untouched, so re-Syncing without pivot data doesn't issue a
+// wasted UPDATE).
+func (a *Associator) SyncRows(ctx context.Context, owner interface{}, rows ...Row) error {
+	ownerPK, err := pkValue(owner, a.desc.OwnerPK)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[int64]Row, len(rows))
+	for _, r := range rows {
+		pk, err := pkValue(r.Related, a.desc.RelatedPK)
+		if err != nil {
+			return err
+		}
+		wanted[pk] = r
+	}
+
+	return a.withTx(ctx, func(ctx context.Context, tx bun.IDB) error {
+		var existing []int64
+		if err := tx.NewSelect().
+			Model(reflect.New(a.desc.joinType).Interface()).
+			Column(a.desc.RelatedColumn).
+			Where("? = ?", bun.Ident(a.desc.OwnerColumn), ownerPK).
+			Scan(ctx, &existing); err != nil {
+			return err
+		}
+
+		have := make(map[int64]bool, len(existing))
+		for _, id := range existing {
+			have[id] = true
+		}
+
+		var toAdd, toUpdate []Row
+		for pk, r := range wanted {
+			switch {
+			case !have[pk]:
+				toAdd = append(toAdd, r)
+			case len(r.Columns) > 0:
+				toUpdate = append(toUpdate, r)
+			}
+		}
+
+		var toRemove []int64
+		for _, id := range existing {
+			if _, ok := wanted[id]; !ok {
+				toRemove = append(toRemove, id)
+			}
+		}
+
+		sub := New(tx, a.desc)
+		if len(toAdd) > 0 {
+			if err := sub.AttachRows(ctx, owner, toAdd...); err != nil {
+				return err
+			}
+		}
+		for _, r := range toUpdate {
+			if err := a.updatePivot(ctx, tx, ownerPK, r); err != nil {
+				return err
+			}
+		}
+		if len(toRemove) > 0 {
+			if _, err := tx.NewDelete().
+				Model(reflect.New(a.desc.joinType).Interface()).
+				Where("? = ?", bun.Ident(a.desc.OwnerColumn), ownerPK).
+				Where("? IN (?)", bun.Ident(a.desc.RelatedColumn), bun.In(toRemove)).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// updatePivot rewrites row.Columns onto the existing join row linking
+// ownerPK to row.Related, leaving the owner/related FKs themselves alone.
+func (a *Associator) updatePivot(ctx context.Context, tx bun.IDB, ownerPK int64, row Row) error {
+	relatedPK, err := pkValue(row.Related, a.desc.RelatedPK)
+	if err != nil {
+		return err
+	}
+
+	joinRow := reflect.New(a.desc.joinType)
+	if err := setField(joinRow.Elem(), a.desc.OwnerColumn, ownerPK); err != nil {
+		return err
+	}
+	if err := setField(joinRow.Elem(), a.desc.RelatedColumn, relatedPK); err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(row.Columns))
+	for column, value := range row.Columns {
+		if err := setField(joinRow.Elem(), column, value); err != nil {
+			return err
+		}
+		columns = append(columns, column)
+	}
+
+	_, err = tx.NewUpdate().
+		Model(joinRow.Interface()).
+		Column(columns...).
+		Where("? = ?", bun.Ident(a.desc.OwnerColumn), ownerPK).
+		Where("? = ?", bun.Ident(a.desc.RelatedColumn), relatedPK).
+		Exec(ctx)
+	return err
+}
+
+// Replace drops every existing association for owner and attaches related
+// in its place, in a single transaction.
+func (a *Associator) Replace(ctx context.Context, owner interface{}, related ...interface{}) error {
+	rows := make([]Row, len(related))
+	for i, rel := range related {
+		rows[i] = Row{Related: rel}
+	}
+	return a.ReplaceRows(ctx, owner, rows...)
+}
+
+// ReplaceRows is Replace with pivot columns: every attached row gets
+// row.Columns set, same as AttachRows.
+func (a *Associator) ReplaceRows(ctx context.Context, owner interface{}, rows ...Row) error {
+	ownerPK, err := pkValue(owner, a.desc.OwnerPK)
+	if err != nil {
+		return err
+	}
+
+	return a.withTx(ctx, func(ctx context.Context, tx bun.IDB) error {
+		if _, err := tx.NewDelete().
+			Model(reflect.New(a.desc.joinType).Interface()).
+			Where("? = ?", bun.Ident(a.desc.OwnerColumn), ownerPK).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		return New(tx, a.desc).AttachRows(ctx, owner, rows...)
+	})
+}
+
+// withTx runs fn in a new transaction when a is bound to a *bun.DB, or
+// directly against the current bun.IDB when a is already inside one (bun
+// has no nested transactions).
+func (a *Associator) withTx(ctx context.Context, fn func(ctx context.Context, tx bun.IDB) error) error {
+	db, ok := a.db.(*bun.DB)
+	if !ok {
+		return fn(ctx, a.db)
+	}
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, tx)
+	})
+}
+
+// pkValues looks up column on every model in models.
+func pkValues(models []interface{}, column string) ([]int64, error) {
+	out := make([]int64, len(models))
+	for i, model := range models {
+		pk, err := pkValue(model, column)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = pk
+	}
+	return out, nil
+}
+
+// pkValue returns the int64 value of the field on model whose bun column
+// name is column.
+func pkValue(model interface{}, column string) (int64, error) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	idx, err := fieldIndex(v.Type(), column)
+	if err != nil {
+		return 0, err
+	}
+	return v.Field(idx).Int(), nil
+}
+
+// setField sets the field on row (a struct value, not a pointer) whose bun
+// column name is column to value, converting value to the field's type
+// when it isn't already assignable (e.g. an int literal into an int64
+// field).
+func setField(row reflect.Value, column string, value interface{}) error {
+	idx, err := fieldIndex(row.Type(), column)
+	if err != nil {
+		return err
+	}
+
+	field := row.Field(idx)
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(field.Type()):
+		// no-op, Set below handles it
+	case rv.Type().ConvertibleTo(field.Type()):
+		rv = rv.Convert(field.Type())
+	default:
+		return fmt.Errorf("m2m: cannot set column %q (%s) from %T", column, field.Type(), value)
+	}
+
+	field.Set(rv)
+	return nil
+}
+
+// fieldIndex finds the struct field of t whose bun column name is column,
+// skipping the embedded bun.BaseModel.
+func fieldIndex(t reflect.Type, column string) (int, error) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "BaseModel" {
+			continue
+		}
+		if columnName(f) == column {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("m2m: no field for column %q on %s", column, t.Name())
+}
+
+// columnName returns the bun column name for field: the explicit name in
+// its tag if any, otherwise the snake_case of its Go name.
+func columnName(f reflect.StructField) string {
+	tag := f.Tag.Get("bun")
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return snakeCase(f.Name)
+}
+
+// snakeCase converts a Go exported field name (e.g. "OrderID") to the
+// column name bun derives for it by default ("order_id").
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
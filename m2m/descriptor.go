@@ -0,0 +1,137 @@
+// Package m2m provides a reusable, reflection-based API for writing the
+// join rows behind a many-to-many relation, on top of the
+// rel:has-many / rel:belongs-to pattern already used by OrderM2M, ItemM2M
+// and OrderToItemM2M in package models (bun itself only knows how to read
+// such relations, not write them).
+package m2m
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Descriptor holds the join metadata needed to read and write association
+// rows for a single owner -> related relation: the join table name, the
+// column on the join table that points back at the owner, the column that
+// points at the related row, and the owner/related primary key columns
+// those FKs reference. Extra pivot columns (e.g. quantity, created_at) are
+// not part of this static metadata since they're per-row data, not schema;
+// set them via Row.Columns when calling AttachRows/SyncRows/ReplaceRows.
+type Descriptor struct {
+	JoinTable     string
+	OwnerColumn   string
+	RelatedColumn string
+	OwnerPK       string
+	RelatedPK     string
+
+	joinType reflect.Type
+}
+
+// Describe builds a Descriptor by inspecting bun tags already on owner and
+// join. ownerField is the name of the `rel:has-many,join:<ownerPK>=<ownerColumn>`
+// field on owner (e.g. "ItemsM2M" on OrderM2M). relatedField is the name of
+// the `rel:belongs-to,join:<relatedColumn>=<relatedPK>` field on join that
+// points at related (e.g. "Item" on OrderToItemM2M).
+func Describe(owner, join, related interface{}, ownerField, relatedField string) (*Descriptor, error) {
+	ownerPK, ownerColumn, err := hasManyJoin(owner, ownerField)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedColumn, relatedPK, err := belongsToJoin(join, relatedField)
+	if err != nil {
+		return nil, err
+	}
+
+	joinTable, err := tableName(join)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{
+		JoinTable:     joinTable,
+		OwnerColumn:   ownerColumn,
+		RelatedColumn: relatedColumn,
+		OwnerPK:       ownerPK,
+		RelatedPK:     relatedPK,
+		joinType:      indirectType(reflect.TypeOf(join)),
+	}, nil
+}
+
+// tableName reads the `bun:"table:..."` tag off model's embedded
+// bun.BaseModel, the same tag every model in this repo already declares.
+func tableName(model interface{}) (string, error) {
+	t := indirectType(reflect.TypeOf(model))
+	field, ok := t.FieldByName("BaseModel")
+	if !ok {
+		return "", fmt.Errorf("m2m: %s has no embedded bun.BaseModel", t.Name())
+	}
+
+	for _, part := range strings.Split(field.Tag.Get("bun"), ",") {
+		if name, ok := strings.CutPrefix(part, "table:"); ok {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("m2m: %s bun.BaseModel tag has no table name", t.Name())
+}
+
+// hasManyJoin reads the rel:has-many,join:<ownerPK>=<ownerColumn> tag off
+// fieldName on owner.
+func hasManyJoin(owner interface{}, fieldName string) (ownerPK, ownerColumn string, err error) {
+	t := indirectType(reflect.TypeOf(owner))
+	field, ok := t.FieldByName(fieldName)
+	if !ok {
+		return "", "", fmt.Errorf("m2m: %s has no field %s", t.Name(), fieldName)
+	}
+
+	return parseJoin(field.Tag.Get("bun"), "rel:has-many")
+}
+
+// belongsToJoin reads the rel:belongs-to,join:<relatedColumn>=<relatedPK>
+// tag off fieldName on join.
+func belongsToJoin(join interface{}, fieldName string) (relatedColumn, relatedPK string, err error) {
+	t := indirectType(reflect.TypeOf(join))
+	field, ok := t.FieldByName(fieldName)
+	if !ok {
+		return "", "", fmt.Errorf("m2m: %s has no field %s", t.Name(), fieldName)
+	}
+
+	return parseJoin(field.Tag.Get("bun"), "rel:belongs-to")
+}
+
+// parseJoin extracts the "left=right" pair out of a bun:"...,join:left=right"
+// tag, after checking the tag declares the expected relation kind.
+func parseJoin(tag, wantRel string) (left, right string, err error) {
+	var hasRel bool
+	for _, part := range strings.Split(tag, ",") {
+		switch {
+		case part == wantRel:
+			hasRel = true
+		case strings.HasPrefix(part, "join:"):
+			pair := strings.TrimPrefix(part, "join:")
+			l, r, ok := strings.Cut(pair, "=")
+			if !ok {
+				return "", "", fmt.Errorf("m2m: malformed join tag %q", part)
+			}
+			left, right = l, r
+		}
+	}
+
+	if !hasRel {
+		return "", "", fmt.Errorf("m2m: tag %q does not declare %s", tag, wantRel)
+	}
+	if left == "" || right == "" {
+		return "", "", fmt.Errorf("m2m: tag %q has no join pair", tag)
+	}
+
+	return left, right, nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
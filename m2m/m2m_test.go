@@ -0,0 +1,136 @@
+package m2m_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"github.com/galihrivanto/bun-m2m/m2m"
+	"github.com/galihrivanto/bun-m2m/migrations"
+	"github.com/galihrivanto/bun-m2m/models"
+)
+
+func newTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := migrations.Run(ctx, db, "up"); err != nil {
+		t.Fatal(err)
+	}
+	db.RegisterModel((*models.OrderToItemM2M)(nil))
+
+	if _, err := db.NewInsert().Model(&models.Order{ID: 1}).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	items := []*models.Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func newOrderItemsDescriptor(t *testing.T) *m2m.Descriptor {
+	t.Helper()
+
+	desc, err := m2m.Describe(
+		(*models.OrderM2M)(nil), (*models.OrderToItemM2M)(nil), (*models.ItemM2M)(nil),
+		"ItemsM2M", "Item",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return desc
+}
+
+// attachedItemIDs returns the item ids attached to orderID, in order.
+func attachedItemIDs(t *testing.T, db *bun.DB, orderID int64) []int64 {
+	t.Helper()
+
+	var ids []int64
+	if err := db.NewSelect().
+		Model((*models.OrderToItem)(nil)).
+		Column("item_id").
+		Where("order_id = ?", orderID).
+		Order("item_id ASC").
+		Scan(context.Background(), &ids); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestAttachIgnoresAlreadyAttachedPairs(t *testing.T) {
+	db := newTestDB(t)
+	assoc := m2m.New(db, newOrderItemsDescriptor(t))
+	ctx := context.Background()
+	order := &models.OrderM2M{ID: 1}
+
+	if err := assoc.Attach(ctx, order, &models.ItemM2M{ID: 1}, &models.ItemM2M{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-attaching an already-linked pair alongside a new one must not
+	// error (ON CONFLICT DO NOTHING) and must still add the new pair.
+	if err := assoc.Attach(ctx, order, &models.ItemM2M{ID: 1}, &models.ItemM2M{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := attachedItemIDs(t, db, 1)
+	want := []int64{1, 2, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("attached items = %v, want %v", got, want)
+	}
+}
+
+func TestSyncAddsAndRemovesInOneCall(t *testing.T) {
+	db := newTestDB(t)
+	assoc := m2m.New(db, newOrderItemsDescriptor(t))
+	ctx := context.Background()
+	order := &models.OrderM2M{ID: 1}
+
+	if err := assoc.Attach(ctx, order, &models.ItemM2M{ID: 1}, &models.ItemM2M{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sync to {2,3}: drops 1, keeps 2, adds 3 — all in the same call.
+	if err := assoc.Sync(ctx, order, &models.ItemM2M{ID: 2}, &models.ItemM2M{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := attachedItemIDs(t, db, 1)
+	want := []int64{2, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("attached items = %v, want %v", got, want)
+	}
+}
+
+func TestReplaceAgainstEmptySet(t *testing.T) {
+	db := newTestDB(t)
+	assoc := m2m.New(db, newOrderItemsDescriptor(t))
+	ctx := context.Background()
+	order := &models.OrderM2M{ID: 1}
+
+	if err := assoc.Attach(ctx, order, &models.ItemM2M{ID: 1}, &models.ItemM2M{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := assoc.Replace(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := attachedItemIDs(t, db, 1); len(got) != 0 {
+		t.Fatalf("attached items = %v, want none", got)
+	}
+}
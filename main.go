@@ -6,83 +6,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"sync"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/driver/sqliteshim"
 	"github.com/uptrace/bun/extra/bundebug"
-)
-
-type Order struct {
-	bun.BaseModel `bun:"table:orders,alias:orders"`
-	ID            int64 `bun:",pk,autoincrement"`
-	ItemID        int64
-}
-
-type Item struct {
-	bun.BaseModel `bun:"table:items,alias:items"`
-	ID            int64 `bun:",pk,autoincrement"`
-	OrderID       int64
-}
-
-type OrderToItem struct {
-	bun.BaseModel `bun:"table:order_to_items,alias:order_to_items"`
-	OrderID       int64 `bun:",pk"`
-	ItemID        int64 `bun:",pk"`
-}
-
-type OrderM2M struct {
-	bun.BaseModel `bun:"table:orders,alias:orders"`
-	ID            int64 `bun:",pk,autoincrement"`
-	ItemID        int64
-
-	// Order and Item in join:Order=Item are fields in OrderToItem model
-	ItemsM2M []OrderToItemM2M `bun:"rel:has-many,join:id=order_id" json:"-"`
-
-	Items []ItemM2M `bun:"-" json:",omitempty"`
-}
-
-func (o *OrderM2M) PostQuery() {
-	o.Items = make([]ItemM2M, 0)
-	if len(o.ItemsM2M) > 0 {
-		for _, item := range o.ItemsM2M {
-			if item.Item != nil {
-				o.Items = append(o.Items, *item.Item)
-			}
-		}
-	}
-}
-
-type ItemM2M struct {
-	bun.BaseModel `bun:"table:items,alias:items"`
-	ID            int64 `bun:",pk,autoincrement"`
-	OrderID       int64
-
-	// Order and Item in join:Order=Item are fields in OrderToItem model
-	OrdersM2M []OrderToItemM2M `bun:"rel:has-many,join:id=item_id" json:"-"`
 
-	Orders []OrderM2M `bun:"-" json:",omitempty"`
-}
-
-func (i *ItemM2M) PostQuery() {
-	i.Orders = make([]OrderM2M, 0)
-	if len(i.OrdersM2M) > 0 {
-		for _, order := range i.OrdersM2M {
-			if order.Order != nil {
-				i.Orders = append(i.Orders, *order.Order)
-			}
-		}
-	}
-}
-
-type OrderToItemM2M struct {
-	bun.BaseModel `bun:"table:order_to_items,alias:order_to_items_m2m"`
-	OrderID       int64     `bun:",pk"`
-	Order         *OrderM2M `bun:"rel:belongs-to,join:order_id=id" json:",omitempty"`
-	ItemID        int64     `bun:",pk"`
-	Item          *ItemM2M  `bun:"rel:belongs-to,join:item_id=id" json:",omitempty"`
-}
+	"github.com/galihrivanto/bun-m2m/httpapi"
+	"github.com/galihrivanto/bun-m2m/migrations"
+	"github.com/galihrivanto/bun-m2m/models"
+	"github.com/galihrivanto/bun-m2m/registry"
+)
 
 func main() {
 	ctx := context.Background()
@@ -97,15 +34,49 @@ func main() {
 
 	db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
 
-	if err := createSchema(ctx, db); err != nil {
+	// `go run . migrate up|down|status` manages the schema directly without
+	// running the rest of the demo.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		direction := "up"
+		if len(os.Args) > 2 {
+			direction = os.Args[2]
+		}
+		if err := migrations.Run(ctx, db, direction); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if err := migrations.Run(ctx, db, "up"); err != nil {
 		panic(err)
 	}
 
 	// Register many to many model so bun can better recognize m2m relation.
-	// This should be done before you use the model for the first time.
-	// db.RegisterModel((*OrderToItemM2M)(nil))
+	// This should be done before you use the model for the first time. Going
+	// through a ModelRegistry (instead of calling db.RegisterModel directly)
+	// keeps this safe even if a later change registers more models from
+	// multiple goroutines.
+	reg := registry.New(db)
+	reg.Register((*models.OrderToItemM2M)(nil))
+
+	// `go run . serve [addr]` exposes the same data over HTTP instead of
+	// running the goroutine demo below.
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		addr := ":8080"
+		if len(os.Args) > 2 {
+			addr = os.Args[2]
+		}
+		srv, err := httpapi.NewServer(db)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("listening on", addr)
+		if err := http.ListenAndServe(addr, srv.Routes()); err != nil {
+			panic(err)
+		}
+		return
+	}
 
-	// concurrently, bun does not support m2m relation for models with same name.
 	wg := sync.WaitGroup{}
 	wg.Add(3)
 	go func() {
@@ -141,7 +112,7 @@ func main() {
 }
 
 func selectUsingHasMany(ctx context.Context, db *bun.DB) error {
-	model := []OrderToItem{}
+	model := []models.OrderToItem{}
 	if err := db.NewSelect().
 		Model(&model).
 		Join("JOIN orders").
@@ -157,10 +128,7 @@ func selectUsingHasMany(ctx context.Context, db *bun.DB) error {
 }
 
 func selectUsingM2M(ctx context.Context, db *bun.DB) error {
-	// try to re-register before using in query
-	// db.RegisterModel((*OrderToItemM2M)(nil))
-
-	order := new(OrderM2M)
+	order := new(models.OrderM2M)
 	if err := db.NewSelect().
 		Model(order).
 		Relation("ItemsM2M").
@@ -174,7 +142,7 @@ func selectUsingM2M(ctx context.Context, db *bun.DB) error {
 	fmt.Println("Order:", order.ID, "Items:", len(order.Items), toJson(order.Items))
 	fmt.Println()
 
-	order = new(OrderM2M)
+	order = new(models.OrderM2M)
 	if err := db.NewSelect().
 		Model(order).
 		Relation("ItemsM2M").
@@ -187,7 +155,7 @@ func selectUsingM2M(ctx context.Context, db *bun.DB) error {
 	fmt.Println("Order:", order.ID, "Items:", len(order.Items), toJson(order.Items))
 	fmt.Println()
 
-	item := new(ItemM2M)
+	item := new(models.ItemM2M)
 	if err := db.NewSelect().
 		Model(item).
 		Relation("OrdersM2M").
@@ -212,28 +180,13 @@ func toJson(v interface{}) string {
 	return string(jsonStr)
 }
 
-func createSchema(ctx context.Context, db *bun.DB) error {
-	models := []interface{}{
-		(*Order)(nil),
-		(*Item)(nil),
-		(*OrderToItem)(nil),
-	}
-	for _, model := range models {
-		if _, err := db.NewCreateTable().Model(model).Exec(ctx); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func InsertQuery(ctx context.Context, db *bun.DB) error {
 	values := []interface{}{
-		&Item{ID: 1},
-		&Item{ID: 2},
-		&Order{ID: 1},
-		&OrderToItem{OrderID: 1, ItemID: 1},
-		&OrderToItem{OrderID: 1, ItemID: 2},
+		&models.Item{ID: 1},
+		&models.Item{ID: 2},
+		&models.Order{ID: 1},
+		&models.OrderToItem{OrderID: 1, ItemID: 1},
+		&models.OrderToItem{OrderID: 1, ItemID: 2},
 	}
 	for _, value := range values {
 		if _, err := db.NewInsert().Model(value).Exec(ctx); err != nil {
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"github.com/galihrivanto/bun-m2m/migrations"
+	"github.com/galihrivanto/bun-m2m/models"
+	"github.com/galihrivanto/bun-m2m/registry"
+)
+
+// TestConcurrentM2MStress mirrors main's actual concurrency shape — one
+// goroutine inserting while others select — repeated a few thousand times,
+// to make sure ModelRegistry's locking holds up under a write racing reads:
+// no panics and no query errors. The relations selectUsingM2M loads
+// (ItemsM2M.Item, OrdersM2M.Order) are not inspected.
+func TestConcurrentM2MStress(t *testing.T) {
+	rounds := 3000
+	if testing.Short() {
+		rounds = 50
+	}
+
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := migrations.Run(ctx, db, "up"); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := registry.New(db)
+	reg.Register((*models.OrderToItemM2M)(nil))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, rounds+1)
+
+	// The one inserting goroutine: each round inserts a fresh order and a
+	// pair of items linked to it, so it keeps writing for as long as the
+	// select goroutines below are reading.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			orderID := int64(i) + 1
+			itemID := orderID * 2
+
+			values := []interface{}{
+				&models.Item{ID: itemID},
+				&models.Item{ID: itemID + 1},
+				&models.Order{ID: orderID},
+				&models.OrderToItem{OrderID: orderID, ItemID: itemID},
+				&models.OrderToItem{OrderID: orderID, ItemID: itemID + 1},
+			}
+			for _, v := range values {
+				if _, err := db.NewInsert().Model(v).Exec(ctx); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}
+	}()
+
+	// Many goroutines selecting concurrently with the insert goroutine
+	// above, the same "models with same name" race the registry exists to
+	// fix.
+	for i := 0; i < rounds; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := selectUsingM2M(ctx, db); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("stress: %v", err)
+	}
+}
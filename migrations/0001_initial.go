@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Register(20230815120000, "initial", upInitial, downInitial)
+}
+
+// these mirror the bun models in package models; migrations intentionally
+// keep their own copies so later schema changes don't rewrite history.
+type order struct {
+	bun.BaseModel `bun:"table:orders,alias:orders"`
+	ID            int64 `bun:",pk,autoincrement"`
+	ItemID        int64
+}
+
+type item struct {
+	bun.BaseModel `bun:"table:items,alias:items"`
+	ID            int64 `bun:",pk,autoincrement"`
+	OrderID       int64
+}
+
+type orderToItem struct {
+	bun.BaseModel `bun:"table:order_to_items,alias:order_to_items"`
+	OrderID       int64 `bun:",pk"`
+	ItemID        int64 `bun:",pk"`
+}
+
+func upInitial(ctx context.Context, tx bun.Tx) error {
+	models := []interface{}{
+		(*order)(nil),
+		(*item)(nil),
+		(*orderToItem)(nil),
+	}
+	for _, model := range models {
+		if _, err := tx.NewCreateTable().Model(model).IfNotExists().Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downInitial(ctx context.Context, tx bun.Tx) error {
+	models := []interface{}{
+		(*orderToItem)(nil),
+		(*item)(nil),
+		(*order)(nil),
+	}
+	for _, model := range models {
+		if _, err := tx.NewDropTable().Model(model).IfExists().Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// lockKey is an arbitrary, fixed constant passed to pg_advisory_lock so every
+// instance of this app contends on the same key regardless of database
+// contents.
+const lockKey = 72261
+
+// lock acquires a session-level Postgres advisory lock so that concurrent
+// instances don't apply migrations at the same time, returning a function
+// that releases it. On dialects without advisory locks (e.g. SQLite, used
+// by the demo) it is a no-op.
+func lock(ctx context.Context, db *bun.DB) (func(), error) {
+	if db.Dialect().Name() != dialect.PG {
+		return func() {}, nil
+	}
+
+	// The lock belongs to one session, so take and release it on the same
+	// pinned connection rather than whichever one the pool hands out.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(?)", lockKey); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(?)", lockKey)
+		conn.Close()
+	}, nil
+}
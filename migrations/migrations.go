@@ -0,0 +1,165 @@
+// Package migrations implements a small goose-style, versioned schema
+// migration runner on top of bun. Each migration is registered with an
+// id in YYYYMMDDHHMMSS form (so migrations apply in timestamp order),
+// an up function and a down function. Applied versions are tracked in a
+// schema_migrations table, and each step runs inside its own transaction.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/uptrace/bun"
+)
+
+// MigrateFunc applies (or reverts) a single migration against tx.
+type MigrateFunc func(ctx context.Context, tx bun.Tx) error
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      MigrateFunc
+	Down    MigrateFunc
+}
+
+var registry []Migration
+
+// Register adds a migration to the set that Run will consider. Migrations
+// are registered from init(), one file per migration (e.g. 0001_initial.go
+// registers version 20230815120000).
+func Register(version int64, name string, up, down MigrateFunc) {
+	registry = append(registry, Migration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+		Down:    down,
+	})
+}
+
+// schemaMigration is the row stored in schema_migrations for every applied
+// migration.
+type schemaMigration struct {
+	bun.BaseModel `bun:"table:schema_migrations,alias:schema_migrations"`
+	Version       int64 `bun:",pk"`
+}
+
+func sorted() []Migration {
+	out := make([]Migration, len(registry))
+	copy(out, registry)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+func ensureTable(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().Model((*schemaMigration)(nil)).IfNotExists().Exec(ctx)
+	return err
+}
+
+func applied(ctx context.Context, db *bun.DB) (map[int64]bool, error) {
+	var rows []schemaMigration
+	if err := db.NewSelect().Model(&rows).Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	out := make(map[int64]bool, len(rows))
+	for _, r := range rows {
+		out[r.Version] = true
+	}
+	return out, nil
+}
+
+// Run drives the migration set in the given direction: "up" applies every
+// pending migration, "down" reverts the most recently applied one, and
+// "status" reports applied/pending migrations without changing anything.
+// A Postgres advisory lock is held for the duration of the run so multiple
+// instances starting at once don't race on the same schema; on SQLite
+// (and any other dialect without advisory locks) this is a no-op.
+func Run(ctx context.Context, db *bun.DB, direction string) error {
+	if err := ensureTable(ctx, db); err != nil {
+		return fmt.Errorf("migrations: ensure schema_migrations: %w", err)
+	}
+
+	unlock, err := lock(ctx, db)
+	if err != nil {
+		return fmt.Errorf("migrations: acquire lock: %w", err)
+	}
+	defer unlock()
+
+	done, err := applied(ctx, db)
+	if err != nil {
+		return fmt.Errorf("migrations: load applied versions: %w", err)
+	}
+
+	switch direction {
+	case "up":
+		return runUp(ctx, db, done)
+	case "down":
+		return runDown(ctx, db, done)
+	case "status":
+		return runStatus(done)
+	default:
+		return fmt.Errorf("migrations: unknown direction %q (want up, down or status)", direction)
+	}
+}
+
+func runUp(ctx context.Context, db *bun.DB, done map[int64]bool) error {
+	for _, m := range sorted() {
+		if done[m.Version] {
+			continue
+		}
+
+		if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := m.Up(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.NewInsert().Model(&schemaMigration{Version: m.Version}).Exec(ctx)
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrations: up %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		fmt.Printf("migrations: applied %d_%s\n", m.Version, m.Name)
+	}
+
+	return nil
+}
+
+func runDown(ctx context.Context, db *bun.DB, done map[int64]bool) error {
+	all := sorted()
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		if !done[m.Version] {
+			continue
+		}
+
+		if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := m.Down(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.NewDelete().Model((*schemaMigration)(nil)).Where("version = ?", m.Version).Exec(ctx)
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrations: down %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		fmt.Printf("migrations: reverted %d_%s\n", m.Version, m.Name)
+		return nil
+	}
+
+	fmt.Println("migrations: nothing to revert")
+	return nil
+}
+
+func runStatus(done map[int64]bool) error {
+	for _, m := range sorted() {
+		state := "pending"
+		if done[m.Version] {
+			state = "applied"
+		}
+		fmt.Printf("%d_%s: %s\n", m.Version, m.Name, state)
+	}
+
+	return nil
+}
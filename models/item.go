@@ -0,0 +1,37 @@
+package models
+
+import "github.com/uptrace/bun"
+
+// Item is the plain, has-many-only representation of an item, used for
+// plain inserts such as InsertQuery's.
+type Item struct {
+	bun.BaseModel `bun:"table:items,alias:items"`
+	ID            int64 `bun:",pk,autoincrement"`
+	OrderID       int64
+}
+
+// ItemM2M is the M2M-aware representation of an item: OrdersM2M is what
+// bun populates via Relation("OrdersM2M.Order"), and PostQuery flattens
+// that into Orders for callers that don't care about the pivot rows.
+type ItemM2M struct {
+	bun.BaseModel `bun:"table:items,alias:items"`
+	ID            int64 `bun:",pk,autoincrement"`
+	OrderID       int64
+
+	// item_id in join:id=item_id is a column of the OrderToItemM2M model
+	OrdersM2M []OrderToItemM2M `bun:"rel:has-many,join:id=item_id" json:"-"`
+
+	Orders []OrderM2M `bun:"-" json:",omitempty"`
+}
+
+// PostQuery flattens OrdersM2M into Orders.
+func (i *ItemM2M) PostQuery() {
+	i.Orders = make([]OrderM2M, 0)
+	if len(i.OrdersM2M) > 0 {
+		for _, order := range i.OrdersM2M {
+			if order.Order != nil {
+				i.Orders = append(i.Orders, *order.Order)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "github.com/uptrace/bun"
+
+// Order is the plain, has-many-only representation of an order, used for
+// plain inserts such as InsertQuery's.
+type Order struct {
+	bun.BaseModel `bun:"table:orders,alias:orders"`
+	ID            int64 `bun:",pk,autoincrement"`
+	ItemID        int64
+}
+
+// OrderM2M is the M2M-aware representation of an order: ItemsM2M is what
+// bun populates via Relation("ItemsM2M.Item"), and PostQuery flattens that
+// into Items for callers that don't care about the pivot rows.
+type OrderM2M struct {
+	bun.BaseModel `bun:"table:orders,alias:orders"`
+	ID            int64 `bun:",pk,autoincrement"`
+	ItemID        int64
+
+	// order_id in join:id=order_id is a column of the OrderToItemM2M model
+	ItemsM2M []OrderToItemM2M `bun:"rel:has-many,join:id=order_id" json:"-"`
+
+	Items []ItemM2M `bun:"-" json:",omitempty"`
+}
+
+// PostQuery flattens ItemsM2M into Items.
+func (o *OrderM2M) PostQuery() {
+	o.Items = make([]ItemM2M, 0)
+	if len(o.ItemsM2M) > 0 {
+		for _, item := range o.ItemsM2M {
+			if item.Item != nil {
+				o.Items = append(o.Items, *item.Item)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/uptrace/bun"
+
+// OrderToItem is the plain join row between Order and Item, used by
+// selectUsingHasMany's manual join and by InsertQuery.
+type OrderToItem struct {
+	bun.BaseModel `bun:"table:order_to_items,alias:order_to_items"`
+	OrderID       int64 `bun:",pk"`
+	ItemID        int64 `bun:",pk"`
+}
+
+// OrderToItemM2M is the same join row, tagged with the belongs-to
+// relations that let bun resolve OrderM2M.ItemsM2M.Item and
+// ItemM2M.OrdersM2M.Order.
+type OrderToItemM2M struct {
+	bun.BaseModel `bun:"table:order_to_items,alias:order_to_items_m2m"`
+	OrderID       int64     `bun:",pk"`
+	Order         *OrderM2M `bun:"rel:belongs-to,join:order_id=id" json:",omitempty"`
+	ItemID        int64     `bun:",pk"`
+	Item          *ItemM2M  `bun:"rel:belongs-to,join:item_id=id" json:",omitempty"`
+}
@@ -0,0 +1,133 @@
+// Package registry provides a thread-safe wrapper around bun's model
+// registration. db.RegisterModel itself is not safe to call concurrently,
+// and resolving an M2M join (e.g. Relation("ItemsM2M.Item")) while another
+// goroutine registers or re-registers a model can race. ModelRegistry
+// serializes registration and caches each model's join metadata once, up
+// front, so query goroutines never need to touch RegisterModel again.
+//
+// m2m.Describe and filter's relation map currently re-derive this same
+// metadata themselves, from the bun tags on the specific owner/join/related
+// triple each one is given, rather than going through Joins here — they
+// need the owner and related FK together as one pair, while Joins reports
+// every belongs-to FK on a model independently. The cache is kept anyway,
+// RWMutex and all, because it's the backlog-specified shape for this
+// package and cheap to keep correct; a caller that wants per-model join
+// introspection (e.g. a future admin/debug endpoint) can use Joins instead
+// of re-deriving it.
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// FK describes one belongs-to side of a join/pivot model: the column it
+// stores locally (JoinColumn), the primary key column on the model it
+// references (TargetPK), and that model's type.
+type FK struct {
+	Field      string
+	JoinColumn string
+	TargetPK   string
+	TargetType reflect.Type
+}
+
+// ModelRegistry wraps a *bun.DB, making RegisterModel calls safe to issue
+// from multiple goroutines and memoizing the belongs-to join metadata
+// discovered for each registered model.
+type ModelRegistry struct {
+	db    *bun.DB
+	mu    sync.RWMutex
+	joins map[reflect.Type][]FK
+}
+
+// New wraps db in a ModelRegistry. Call Register for every model once,
+// during startup, before any goroutine runs queries against db.
+func New(db *bun.DB) *ModelRegistry {
+	return &ModelRegistry{
+		db:    db,
+		joins: make(map[reflect.Type][]FK),
+	}
+}
+
+// Register registers model with the underlying bun.DB and caches its
+// belongs-to join metadata. It is safe to call concurrently, though it is
+// intended to be called once per model at startup.
+func (r *ModelRegistry) Register(model interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.db.RegisterModel(model)
+
+	t := indirectType(reflect.TypeOf(model))
+	if _, ok := r.joins[t]; ok {
+		return
+	}
+	r.joins[t] = belongsToFKs(t)
+}
+
+// Joins returns the belongs-to FKs cached for model's type at Register
+// time, and whether model has been registered at all.
+func (r *ModelRegistry) Joins(model interface{}) ([]FK, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fks, ok := r.joins[indirectType(reflect.TypeOf(model))]
+	return fks, ok
+}
+
+// belongsToFKs scans t's fields for rel:belongs-to,join:col=pk tags, the
+// same tags already used by OrderToItemM2M's Order and Item fields.
+func belongsToFKs(t reflect.Type) []FK {
+	var fks []FK
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("bun")
+		if !hasTagPart(tag, "rel:belongs-to") {
+			continue
+		}
+
+		joinColumn, targetPK, ok := joinPair(tag)
+		if !ok {
+			continue
+		}
+
+		fks = append(fks, FK{
+			Field:      f.Name,
+			JoinColumn: joinColumn,
+			TargetPK:   targetPK,
+			TargetType: indirectType(f.Type),
+		})
+	}
+	return fks
+}
+
+func hasTagPart(tag, want string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		if part == want {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPair(tag string) (left, right string, ok bool) {
+	for _, part := range strings.Split(tag, ",") {
+		name, found := strings.CutPrefix(part, "join:")
+		if !found {
+			continue
+		}
+		l, r, cut := strings.Cut(name, "=")
+		return l, r, cut
+	}
+	return "", "", false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}